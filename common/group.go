@@ -0,0 +1,202 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// watchPollInterval is how often Group polls a child's Stopped() method when
+// the child does not implement doneNotifier.
+const watchPollInterval = 200 * time.Millisecond
+
+// doneNotifier is implemented by services that can signal their own
+// termination through a channel, instead of being polled through Stopped().
+type doneNotifier interface {
+	Done() <-chan struct{}
+}
+
+// Service describes a child Lifecycle managed by a Group, along with the
+// supervision policy that applies to it.
+type Service struct {
+	// Name identifies the service in logs and aggregated errors.
+	Name string
+	// Lifecycle is the managed service.
+	Lifecycle Lifecycle
+	// StartTimeout bounds how long Start may take. Zero means no timeout.
+	StartTimeout time.Duration
+	// StopTimeout bounds how long Stop may take. Zero means no timeout.
+	StopTimeout time.Duration
+	// Optional services that fail to start, or exit unexpectedly while
+	// running, are logged rather than aborting the whole Group.
+	Optional bool
+}
+
+// Group manages a set of services as a single Lifecycle.
+//
+// Start brings services up in registration order; if any required service
+// fails to start, Group rolls back by stopping the services that already
+// started, in reverse order, before returning the combined error. Stop
+// brings services down in reverse order, joining all errors encountered
+// via errors.Join.
+//
+// While running, Group supervises each required service: if one exits on
+// its own (outside of a Group.Stop call), Group aborts the context passed
+// to Start via Abort, so the owning LifecycleCmd can unwind the rest of the
+// application instead of hanging indefinitely.
+//
+// Group embeds BaseLifecycle and drives it through Idle -> Starting ->
+// Started -> Stopping -> Stopped (or Failed, if Start fails), so it can be
+// used as a StatefulLifecycle by LifecycleCmd.
+type Group struct {
+	BaseLifecycle
+
+	services []Service
+
+	mu      sync.Mutex
+	started []Service  // services that successfully started, in start order
+	halting bool       // true once Stop or a start rollback has begun
+
+	wg sync.WaitGroup
+}
+
+// NewGroup creates a Group that manages the given services in registration order.
+func NewGroup(services ...Service) *Group {
+	return &Group{services: services}
+}
+
+func (g *Group) Start(ctx context.Context) error {
+	g.SetState(Starting)
+	for _, svc := range g.services {
+		startCtx := ctx
+		cancel := context.CancelFunc(func() {})
+		if svc.StartTimeout > 0 {
+			startCtx, cancel = context.WithTimeout(ctx, svc.StartTimeout)
+		}
+		err := svc.Lifecycle.Start(startCtx)
+		cancel()
+		if err != nil {
+			if svc.Optional {
+				log.Printf("common: optional service %q failed to start: %v", svc.Name, err)
+				continue
+			}
+			rollbackErr := g.rollback(ctx)
+			g.SetState(Failed)
+			return errors.Join(fmt.Errorf("service %q failed to start: %w", svc.Name, err), rollbackErr)
+		}
+
+		g.mu.Lock()
+		g.started = append(g.started, svc)
+		g.mu.Unlock()
+
+		g.wg.Add(1)
+		go g.watch(ctx, svc)
+	}
+	g.SetState(Started)
+	return nil
+}
+
+// rollback stops the services that already started, in reverse order, used
+// when a later service fails to start.
+func (g *Group) rollback(ctx context.Context) error {
+	g.mu.Lock()
+	g.halting = true
+	started := g.started
+	g.started = nil
+	g.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		svc := started[i]
+		stopCtx := ctx
+		cancel := context.CancelFunc(func() {})
+		if svc.StopTimeout > 0 {
+			stopCtx, cancel = context.WithTimeout(ctx, svc.StopTimeout)
+		}
+		err := svc.Lifecycle.Stop(stopCtx)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("service %q failed to stop during rollback: %w", svc.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// watch observes a started service and aborts ctx if it exits on its own.
+func (g *Group) watch(ctx context.Context, svc Service) {
+	defer g.wg.Done()
+
+	if dn, ok := svc.Lifecycle.(doneNotifier); ok {
+		select {
+		case <-dn.Done():
+		case <-ctx.Done():
+			return
+		}
+	} else {
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if svc.Lifecycle.Stopped() {
+					goto exited
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	exited:
+	}
+
+	g.mu.Lock()
+	halting := g.halting
+	g.mu.Unlock()
+	if halting {
+		// Expected: this service exited as part of an ongoing Stop.
+		return
+	}
+
+	if svc.Optional {
+		log.Printf("common: optional service %q exited unexpectedly", svc.Name)
+		return
+	}
+	Abort(ctx, fmt.Errorf("required service %q exited unexpectedly", svc.Name))
+}
+
+func (g *Group) Stop(ctx context.Context) error {
+	g.SetState(Stopping)
+
+	g.mu.Lock()
+	g.halting = true
+	started := g.started
+	g.started = nil
+	g.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		svc := started[i]
+		stopCtx := ctx
+		cancel := context.CancelFunc(func() {})
+		if svc.StopTimeout > 0 {
+			stopCtx, cancel = context.WithTimeout(ctx, svc.StopTimeout)
+		}
+		err := svc.Lifecycle.Stop(stopCtx)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("service %q failed to stop: %w", svc.Name, err))
+		}
+	}
+
+	g.wg.Wait()
+	g.SetState(Stopped)
+	return errors.Join(errs...)
+}
+
+func (g *Group) Stopped() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.halting && len(g.started) == 0
+}
@@ -0,0 +1,88 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeService is a minimal Lifecycle used by Group tests.
+type fakeService struct {
+	name     string
+	startErr error
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+}
+
+func (f *fakeService) Start(ctx context.Context) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.mu.Lock()
+	f.started = true
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeService) Stop(ctx context.Context) error {
+	f.mu.Lock()
+	f.stopped = true
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeService) Stopped() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stopped
+}
+
+func TestGroupStartFailureRollback(t *testing.T) {
+	a := &fakeService{name: "a"}
+	b := &fakeService{name: "b"}
+	failing := &fakeService{name: "failing", startErr: errors.New("boom")}
+
+	g := NewGroup(
+		Service{Name: "a", Lifecycle: a},
+		Service{Name: "b", Lifecycle: b},
+		Service{Name: "failing", Lifecycle: failing},
+	)
+
+	if err := g.Start(context.Background()); err == nil {
+		t.Fatal("expected Start to fail")
+	}
+	if !a.Stopped() || !b.Stopped() {
+		t.Fatal("expected already-started services to be rolled back")
+	}
+}
+
+func TestGroupAbortsOnUnexpectedExit(t *testing.T) {
+	svc := &fakeService{name: "worker"}
+	g := NewGroup(Service{Name: "worker", Lifecycle: svc})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	runCtx := context.WithValue(ctx, abortKey{}, cancel)
+
+	if err := g.Start(runCtx); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+
+	// Simulate the service exiting on its own, without Group.Stop being called.
+	svc.mu.Lock()
+	svc.stopped = true
+	svc.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the context to be aborted after the unexpected exit")
+	}
+	if context.Cause(ctx) == nil {
+		t.Fatal("expected an abort cause to be set")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+
 	"github.com/urfave/cli/v2"
 )
 
@@ -20,12 +22,37 @@ type Lifecycle interface {
 	Stopped() bool
 }
 
+// CancellableLifecycle is a Lifecycle that additionally supports forcibly
+// cancelling in-flight work, for use when a graceful Stop did not complete
+// within its drain deadline.
+type CancellableLifecycle interface {
+	Lifecycle
+	// StopAndCancel stops the service like Stop, but cancels worker loops
+	// and in-flight operations instead of waiting for them to drain.
+	StopAndCancel(ctx context.Context) error
+}
+
 type LifecycleAction func(ctx *cli.Context) (Lifecycle, error)
 
+// abortKey is the context key under which LifecycleCmd stores the
+// CancelCauseFunc of appCtx, so nested services can request an
+// application-wide shutdown through Abort.
+type abortKey struct{}
+
+// Abort cancels the appCtx established by LifecycleCmd with the given
+// cause, if ctx was derived from it. It is a no-op otherwise, so it is
+// safe to call from services that run outside of a LifecycleCmd.
+func Abort(ctx context.Context, cause error) {
+	if cancel, ok := ctx.Value(abortKey{}).(context.CancelCauseFunc); ok {
+		cancel(cause)
+	}
+}
+
 func LifecycleCmd(fn LifecycleAction) cli.ActionFunc {
 	return func(ctx *cli.Context) error {
 		hostCtx := ctx.Context
-		appCtx, _ := context.WithCancelCause(hostCtx)
+		appCtx, cancelApp := context.WithCancelCause(hostCtx)
+		appCtx = context.WithValue(appCtx, abortKey{}, cancelApp)
 		ctx.Context = appCtx
 
 		appLifecycle, err := fn(ctx)
@@ -37,6 +64,26 @@ func LifecycleCmd(fn LifecycleAction) cli.ActionFunc {
 			)
 		}
 
+		// fn may have wrapped ctx.Context further (e.g. to attach its own
+		// values); re-read it here so the lifetime context snapshotted below
+		// includes anything it added, instead of only what was visible
+		// before fn ran.
+		appCtx = ctx.Context
+		appCtx = context.WithValue(appCtx, lifetimeKey{}, context.WithoutCancel(appCtx))
+		ctx.Context = appCtx
+
+		// Trap signals for the lifetime of the app: a first SIGINT/SIGTERM
+		// requests a graceful stop, SIGHUP triggers a reload, and a second
+		// SIGINT/SIGTERM (received once shutdown has begun) forces an
+		// immediate stop.
+		sigHandler := newSignalHandler(cancelApp, appLifecycle)
+		sigCtx, stopSigHandler := context.WithCancel(hostCtx)
+		defer stopSigHandler()
+		go sigHandler.run(sigCtx)
+
+		if sl, ok := appLifecycle.(StatefulLifecycle); ok && sl.State() != Idle {
+			return ErrAlreadyStarted
+		}
 		if err := appLifecycle.Start(appCtx); err != nil {
 			// join errors to include context cause (nil errors are dropped)
 			return errors.Join(
@@ -45,23 +92,77 @@ func LifecycleCmd(fn LifecycleAction) cli.ActionFunc {
 			)
 		}
 
+		if rn, ok := appLifecycle.(ReadyNotifier); ok {
+			if err := rn.Ready(appCtx); err != nil {
+				log.Printf("common: ready hook failed: %v", err)
+			}
+		}
+		if err := notifySystemd(sdNotifyReady); err != nil {
+			log.Printf("common: failed to notify systemd of readiness: %v", err)
+		}
+
 		// wait for app to be closed (through interrupt, or app requests to be stopped by closing the context)
 		<-appCtx.Done()
 
-		// Graceful stop context.
-		// This allows the service to idle before shutdown, if halted. User may interrupt.
-		stopCtx, stopCancel := context.WithCancelCause(hostCtx)
+		if err := notifySystemd(sdNotifyStopping); err != nil {
+			log.Printf("common: failed to notify systemd of shutdown: %v", err)
+		}
+
+		if sl, ok := appLifecycle.(StatefulLifecycle); ok {
+			if s := sl.State(); s == Stopping || s == Stopped {
+				return ErrAlreadyStopped
+			}
+		}
 
-		// Execute graceful stop.
-		stopErr := appLifecycle.Stop(stopCtx)
-		stopCancel(nil)
+		// Phase 1: drain. Give the service a chance to finish in-flight work
+		// and idle down gracefully, bounded by --drain-timeout. User may
+		// still interrupt; a second interrupt hammers this phase directly.
+		// Derived from hostCtx (not appCtx itself, which is already Done) so
+		// the caller cancelling its own context still unblocks Stop, but
+		// carrying appCtx's values too so Stop can retrieve them via
+		// LifecycleContext.
+		drainCtx, drainCancel := context.WithCancelCause(valuesFrom(hostCtx, appCtx))
+		if timeout := ctx.Duration(DrainTimeoutFlag.Name); timeout > 0 {
+			var cancelTimeout context.CancelFunc
+			drainCtx, cancelTimeout = context.WithTimeout(drainCtx, timeout)
+			defer cancelTimeout()
+		}
+		sigHandler.armHammer(drainCancel)
+
+		stopErr := appLifecycle.Stop(drainCtx)
+		drainCancel(nil)
 		// note: Stop implementation may choose to suppress a context error,
 		// if it handles it well (e.g. stop idling after a halt).
+
+		// Phase 2: if the drain deadline was exceeded, escalate to
+		// cancelling in-flight work outright, bounded by --hard-timeout.
+		if errors.Is(stopErr, context.DeadlineExceeded) {
+			if cl, ok := appLifecycle.(CancellableLifecycle); ok {
+				hardCtx, hardCancel := context.WithCancelCause(valuesFrom(hostCtx, appCtx))
+				if timeout := ctx.Duration(HardTimeoutFlag.Name); timeout > 0 {
+					var cancelTimeout context.CancelFunc
+					hardCtx, cancelTimeout = context.WithTimeout(hardCtx, timeout)
+					defer cancelTimeout()
+				}
+				sigHandler.armHammer(hardCancel)
+
+				stopErr = cl.StopAndCancel(hardCtx)
+				hardCancel(nil)
+				if stopErr != nil {
+					return errors.Join(
+						fmt.Errorf("failed to stop and cancel: %w", stopErr),
+						context.Cause(hardCtx),
+					)
+				}
+				return nil
+			}
+		}
+
 		if stopErr != nil {
 			// join errors to include context cause (nil errors are dropped)
 			return errors.Join(
 				fmt.Errorf("failed to stop: %w", stopErr),
-				context.Cause(stopCtx),
+				context.Cause(drainCtx),
 			)
 		}
 
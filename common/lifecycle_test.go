@@ -0,0 +1,140 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+// lifetimeProbeLifecycle records what it observes through LifecycleContext
+// from inside Stop.
+type lifetimeProbeLifecycle struct {
+	stopSawErr   error
+	stopSawValue any
+}
+
+type probeKeyType struct{}
+
+var probeKey = probeKeyType{}
+
+func (l *lifetimeProbeLifecycle) Start(ctx context.Context) error {
+	go Abort(ctx, errors.New("test: shutting down"))
+	return nil
+}
+
+func (l *lifetimeProbeLifecycle) Stop(ctx context.Context) error {
+	lc := LifecycleContext(ctx)
+	l.stopSawErr = lc.Err()
+	l.stopSawValue = lc.Value(probeKey)
+	return nil
+}
+
+func (l *lifetimeProbeLifecycle) Stopped() bool { return true }
+
+func TestLifecycleContextReachableDuringStop(t *testing.T) {
+	svc := &lifetimeProbeLifecycle{}
+	app := &cli.App{
+		Action: LifecycleCmd(func(ctx *cli.Context) (Lifecycle, error) {
+			ctx.Context = context.WithValue(ctx.Context, probeKey, "hello")
+			return svc, nil
+		}),
+	}
+
+	if err := app.Run([]string{"app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.stopSawErr != nil {
+		t.Fatalf("expected LifecycleContext to report no error during Stop, got %v", svc.stopSawErr)
+	}
+	if svc.stopSawValue != "hello" {
+		t.Fatalf("expected LifecycleContext to carry values set on the app context, got %v", svc.stopSawValue)
+	}
+}
+
+// readyNotifyingLifecycle records whether Ready was invoked, and whether it
+// was called after Start returned.
+type readyNotifyingLifecycle struct {
+	started    bool
+	readyCalls int
+}
+
+func (l *readyNotifyingLifecycle) Start(ctx context.Context) error {
+	l.started = true
+	go Abort(ctx, errors.New("test: shutting down"))
+	return nil
+}
+
+func (l *readyNotifyingLifecycle) Stop(ctx context.Context) error { return nil }
+
+func (l *readyNotifyingLifecycle) Stopped() bool { return true }
+
+func (l *readyNotifyingLifecycle) Ready(ctx context.Context) error {
+	if !l.started {
+		return errors.New("test: Ready called before Start returned")
+	}
+	l.readyCalls++
+	return nil
+}
+
+func TestLifecycleCmdCallsReadyAfterStart(t *testing.T) {
+	svc := &readyNotifyingLifecycle{}
+	app := &cli.App{
+		Action: LifecycleCmd(func(ctx *cli.Context) (Lifecycle, error) {
+			return svc, nil
+		}),
+	}
+
+	if err := app.Run([]string{"app"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.readyCalls != 1 {
+		t.Fatalf("expected Ready to be called once, got %d", svc.readyCalls)
+	}
+}
+
+// drainThenCancelLifecycle blocks in Stop until its context is done, so a
+// short --drain-timeout always exceeds it and forces escalation.
+type drainThenCancelLifecycle struct {
+	stopCalls          int
+	stopAndCancelCalls int
+}
+
+func (l *drainThenCancelLifecycle) Start(ctx context.Context) error {
+	go Abort(ctx, errors.New("test: shutting down"))
+	return nil
+}
+
+func (l *drainThenCancelLifecycle) Stop(ctx context.Context) error {
+	l.stopCalls++
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (l *drainThenCancelLifecycle) StopAndCancel(ctx context.Context) error {
+	l.stopAndCancelCalls++
+	return nil
+}
+
+func (l *drainThenCancelLifecycle) Stopped() bool { return l.stopAndCancelCalls > 0 }
+
+func TestLifecycleCmdDrainToHardEscalation(t *testing.T) {
+	svc := &drainThenCancelLifecycle{}
+	app := &cli.App{
+		Flags: []cli.Flag{DrainTimeoutFlag, HardTimeoutFlag},
+		Action: LifecycleCmd(func(ctx *cli.Context) (Lifecycle, error) {
+			return svc, nil
+		}),
+	}
+
+	if err := app.Run([]string{"app", "--drain-timeout=10ms", "--hard-timeout=2s"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.stopCalls != 1 {
+		t.Fatalf("expected Stop to be called once, got %d", svc.stopCalls)
+	}
+	if svc.stopAndCancelCalls != 1 {
+		t.Fatalf("expected StopAndCancel to be called once after the drain deadline, got %d", svc.stopAndCancelCalls)
+	}
+}
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+const (
+	sdNotifyReady    = "READY=1"
+	sdNotifyStopping = "STOPPING=1"
+)
+
+// notifySystemd sends a state notification to systemd over the socket
+// named by $NOTIFY_SOCKET, implementing the sd_notify(3) protocol used by
+// Type=notify services. It is a no-op returning nil if $NOTIFY_SOCKET is
+// unset, which is the case when not running under systemd.
+func notifySystemd(state string) error {
+	socketPath := os.Getenv("NOTIFY_SOCKET")
+	if socketPath == "" {
+		return nil
+	}
+
+	addr := &net.UnixAddr{Name: socketPath, Net: "unixgram"}
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		return fmt.Errorf("failed to dial NOTIFY_SOCKET %q: %w", socketPath, err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("failed to write systemd notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNotifySystemdNoSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := notifySystemd(sdNotifyReady); err != nil {
+		t.Fatalf("expected no-op when NOTIFY_SOCKET is unset, got %v", err)
+	}
+}
+
+func TestNotifySystemdSendsState(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "notify.sock")
+	addr := &net.UnixAddr{Name: socketPath, Net: "unixgram"}
+	conn, err := net.ListenUnixgram("unixgram", addr)
+	if err != nil {
+		t.Fatalf("failed to listen on fake NOTIFY_SOCKET: %v", err)
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", socketPath)
+
+	if err := notifySystemd(sdNotifyReady); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read notification: %v", err)
+	}
+	if got := string(buf[:n]); got != sdNotifyReady {
+		t.Fatalf("expected notification %q, got %q", sdNotifyReady, got)
+	}
+
+	if err := notifySystemd(sdNotifyStopping); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err = conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read notification: %v", err)
+	}
+	if got := string(buf[:n]); got != sdNotifyStopping {
+		t.Fatalf("expected notification %q, got %q", sdNotifyStopping, got)
+	}
+}
@@ -0,0 +1,114 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// DrainTimeoutFlag bounds how long the graceful drain phase of shutdown
+// (the initial Stop call) is allowed to run before LifecycleCmd escalates
+// to CancellableLifecycle.StopAndCancel, if the lifecycle supports it. It
+// is independent of the "hammer" behavior, where a second interrupt forces
+// the current phase to end immediately. Commands using LifecycleCmd should
+// include this flag in their own Flags list.
+var DrainTimeoutFlag = &cli.DurationFlag{
+	Name:  "drain-timeout",
+	Usage: "Timeout for the graceful drain phase of shutdown, before forcibly cancelling in-flight work",
+	Value: 5 * time.Second,
+}
+
+// HardTimeoutFlag bounds the forced-cancellation phase of shutdown
+// (StopAndCancel), entered once the drain phase exceeds DrainTimeoutFlag.
+var HardTimeoutFlag = &cli.DurationFlag{
+	Name:  "hard-timeout",
+	Usage: "Timeout for the forced-cancellation phase of shutdown, after the drain phase times out",
+	Value: 30 * time.Second,
+}
+
+// Reloader is implemented by a Lifecycle that supports reloading its
+// configuration in place, e.g. in response to SIGHUP.
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// ReadyNotifier is implemented by a Lifecycle that wants to run its own
+// logic once Start has returned successfully, e.g. to emit a readiness
+// signal of its own alongside LifecycleCmd's systemd notification.
+type ReadyNotifier interface {
+	Ready(ctx context.Context) error
+}
+
+// signalHandler implements the signal-driven shutdown behavior used by
+// LifecycleCmd: the first SIGINT/SIGTERM cancels the app context, a second
+// one received after shutdown has begun cancels the stop context
+// immediately ("hammer time"), and SIGHUP invokes lifecycle.Reload if the
+// lifecycle implements Reloader.
+type signalHandler struct {
+	appCancel context.CancelCauseFunc
+	lifecycle Lifecycle
+
+	mu           sync.Mutex
+	stopping     bool
+	hammerCancel context.CancelCauseFunc
+}
+
+func newSignalHandler(appCancel context.CancelCauseFunc, lifecycle Lifecycle) *signalHandler {
+	return &signalHandler{appCancel: appCancel, lifecycle: lifecycle}
+}
+
+// armHammer enables the second-signal "hammer" behavior: once called, a
+// further SIGINT/SIGTERM cancels the given stop context immediately.
+func (h *signalHandler) armHammer(cancel context.CancelCauseFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopping = true
+	h.hammerCancel = cancel
+}
+
+// run traps signals until ctx is done.
+func (h *signalHandler) run(ctx context.Context) {
+	sigCh := make(chan os.Signal, 4)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigCh:
+			h.handle(sig)
+		}
+	}
+}
+
+func (h *signalHandler) handle(sig os.Signal) {
+	if sig == syscall.SIGHUP {
+		if r, ok := h.lifecycle.(Reloader); ok {
+			go func() {
+				if err := r.Reload(context.Background()); err != nil {
+					log.Printf("common: reload failed: %v", err)
+				}
+			}()
+		}
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.stopping {
+		h.stopping = true
+		h.appCancel(fmt.Errorf("received signal %s", sig))
+		return
+	}
+	if h.hammerCancel != nil {
+		h.hammerCancel(fmt.Errorf("received second signal %s: forcing immediate shutdown", sig))
+	}
+}
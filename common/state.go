@@ -0,0 +1,135 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// State is a lifecycle state, as tracked by BaseLifecycle.
+type State int
+
+const (
+	Idle State = iota
+	Starting
+	Started
+	Stopping
+	Stopped
+	Failed
+)
+
+func (s State) String() string {
+	switch s {
+	case Idle:
+		return "idle"
+	case Starting:
+		return "starting"
+	case Started:
+		return "started"
+	case Stopping:
+		return "stopping"
+	case Stopped:
+		return "stopped"
+	case Failed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	// ErrAlreadyStarted is returned when Start is called on a lifecycle that
+	// has already left the Idle state.
+	ErrAlreadyStarted = errors.New("lifecycle already started")
+	// ErrAlreadyStopped is returned when Stop is called on a lifecycle that
+	// has already reached Stopping or Stopped.
+	ErrAlreadyStopped = errors.New("lifecycle already stopped")
+)
+
+// StatefulLifecycle is a Lifecycle that exposes its state machine, so
+// callers can observe transitions and wait for a particular state instead
+// of polling Stopped().
+type StatefulLifecycle interface {
+	Lifecycle
+	// State returns the current lifecycle state.
+	State() State
+	// WaitFor blocks until the lifecycle reaches state s, or ctx is done,
+	// whichever comes first.
+	WaitFor(ctx context.Context, s State) error
+}
+
+// StateListener is notified of every lifecycle state transition, e.g. to
+// drive metrics or a health endpoint.
+type StateListener interface {
+	OnStateChange(from, to State)
+}
+
+// BaseLifecycle is an embeddable helper that tracks the Idle -> Starting ->
+// Started -> Stopping -> Stopped state machine (with a Failed state
+// reachable from Starting or Stopping) in a thread-safe way. Embedders call
+// SetState to record transitions; State and WaitFor expose them to callers.
+type BaseLifecycle struct {
+	mu       sync.Mutex
+	state    State
+	changed  chan struct{} // closed and replaced on every call to SetState
+	listener StateListener
+}
+
+// SetStateListener registers a listener notified of every future state
+// transition. It does not replay past transitions.
+func (b *BaseLifecycle) SetStateListener(l StateListener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listener = l
+}
+
+// State returns the current lifecycle state.
+func (b *BaseLifecycle) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// SetState records a transition to s and wakes any blocked WaitFor callers.
+// Embedders call this from their Start/Stop implementations to drive the
+// state machine; it is exported so types outside package common can embed
+// BaseLifecycle and still report their own transitions.
+func (b *BaseLifecycle) SetState(s State) {
+	b.mu.Lock()
+	if b.changed == nil {
+		b.changed = make(chan struct{})
+	}
+	from := b.state
+	b.state = s
+	listener := b.listener
+	changed := b.changed
+	b.changed = make(chan struct{})
+	b.mu.Unlock()
+
+	close(changed)
+	if listener != nil && from != s {
+		listener.OnStateChange(from, s)
+	}
+}
+
+// WaitFor blocks until the lifecycle reaches state s, or ctx is done.
+func (b *BaseLifecycle) WaitFor(ctx context.Context, s State) error {
+	for {
+		b.mu.Lock()
+		if b.changed == nil {
+			b.changed = make(chan struct{})
+		}
+		if b.state == s {
+			b.mu.Unlock()
+			return nil
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
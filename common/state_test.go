@@ -0,0 +1,64 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBaseLifecycleTransitions(t *testing.T) {
+	var b BaseLifecycle
+	if b.State() != Idle {
+		t.Fatalf("expected initial state Idle, got %v", b.State())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := b.WaitFor(context.Background(), Started); err != nil {
+			t.Errorf("WaitFor failed: %v", err)
+		}
+	}()
+
+	b.SetState(Starting)
+	b.SetState(Started)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("WaitFor did not observe the Started transition")
+	}
+	if b.State() != Started {
+		t.Fatalf("expected state Started, got %v", b.State())
+	}
+}
+
+func TestBaseLifecycleWaitForContextCancel(t *testing.T) {
+	var b BaseLifecycle
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.WaitFor(ctx, Started); err == nil {
+		t.Fatal("expected WaitFor to return an error for an already-cancelled context")
+	}
+}
+
+func TestGroupDrivesStateMachine(t *testing.T) {
+	svc := &fakeService{name: "worker"}
+	g := NewGroup(Service{Name: "worker", Lifecycle: svc})
+
+	if g.State() != Idle {
+		t.Fatalf("expected Group to start Idle, got %v", g.State())
+	}
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	if g.State() != Started {
+		t.Fatalf("expected Group to be Started after Start, got %v", g.State())
+	}
+	if err := g.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected stop error: %v", err)
+	}
+	if g.State() != Stopped {
+		t.Fatalf("expected Group to be Stopped after Stop, got %v", g.State())
+	}
+}
@@ -0,0 +1,50 @@
+package common
+
+import "context"
+
+// lifetimeKey is the context key under which LifecycleCmd stores the
+// detached lifetime context for a run, so it can be retrieved via
+// LifecycleContext.
+type lifetimeKey struct{}
+
+// LifecycleContext returns the detached lifetime context associated with
+// ctx: a context.Context with the same values as the app context, but that
+// never reports Done or Err (see context.WithoutCancel). Shutdown-time code
+// (DB flushes, final RPCs) should derive from this instead of the app
+// context, so it isn't short-circuited by the cancellation that triggered
+// the shutdown in the first place. It returns ctx itself if no lifetime
+// context was attached.
+func LifecycleContext(ctx context.Context) context.Context {
+	if lc, ok := ctx.Value(lifetimeKey{}).(context.Context); ok {
+		return lc
+	}
+	return ctx
+}
+
+// valueFallbackContext reports Done, Err and Deadline from the embedded base
+// context, but resolves Value against base first and only falls back to
+// values for keys base doesn't have. This lets base's own cancellation chain
+// (and anything stdlib derives from it, e.g. context.Cause's internal
+// bookkeeping) keep working normally, while still exposing values attached
+// further down a separate context (e.g. one built up after base was forked
+// off).
+type valueFallbackContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c valueFallbackContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.values.Value(key)
+}
+
+// valuesFrom returns a context that cancels along with base, but also sees
+// the values carried by values. It is used to derive shutdown-phase contexts
+// that must still observe the caller's own cancellation (base), while also
+// carrying whatever LifecycleCmd or the application attached to the app
+// context (values).
+func valuesFrom(base, values context.Context) context.Context {
+	return valueFallbackContext{Context: base, values: values}
+}